@@ -0,0 +1,166 @@
+package ratelimiter
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis_rate/v10"
+	"github.com/redis/go-redis/v9"
+)
+
+// Algorithm selects how a Limiter checks a key against its rate limit.
+type Algorithm int
+
+const (
+	// GCRA is a smooth, low-latency token bucket backed by redis_rate.
+	// It's the historical default and the right choice when requests
+	// should be shaped evenly. Its burst equals the rate, so a full
+	// period's worth of requests can still pass back to back.
+	GCRA Algorithm = iota
+	// SlidingWindow approximates a sliding window over the current and
+	// previous fixed windows, weighted by elapsed fraction of the
+	// current window. It's cheaper than GCRA and sufficient for coarse
+	// quotas (e.g. "1M requests/day per API key").
+	SlidingWindow
+	// FixedWindow counts requests in the current fixed-size window with
+	// a single INCR/EXPIRE. It's the cheapest option, at the cost of
+	// allowing up to 2x the limit across a window boundary.
+	FixedWindow
+)
+
+// WithAlgorithm selects which Algorithm New/NewKeyed checks keys with.
+// Defaults to GCRA.
+func WithAlgorithm(a Algorithm) Option {
+	return func(c *config) {
+		c.algorithm = a
+	}
+}
+
+// algorithm is the internal strategy a redisLimiter checks a key
+// against. Every Algorithm implements it, so the rest of the code
+// (composite, observer, failure policy, metrics) stays unchanged
+// regardless of which one a key is checked with.
+type algorithm interface {
+	check(ctx context.Context, key string, n int) (allowed bool, retryAfter, resetAfter time.Duration, remaining int, err error)
+}
+
+// newAlgorithm builds the algorithm implementation for a, sharing rdb
+// and enforcing rate requests per period.
+func newAlgorithm(rdb redis.UniversalClient, a Algorithm, rate int, period time.Duration) algorithm {
+	switch a {
+	case SlidingWindow:
+		return &slidingWindowAlgorithm{rdb: rdb, rate: rate, period: period}
+	case FixedWindow:
+		return &fixedWindowAlgorithm{rdb: rdb, rate: rate, period: period}
+	default:
+		return &gcraAlgorithm{
+			limiter: redis_rate.NewLimiter(rdb),
+			limit:   redis_rate.Limit{Rate: rate, Burst: rate, Period: period},
+		}
+	}
+}
+
+// gcraAlgorithm delegates to redis_rate's GCRA (leaky bucket) implementation.
+type gcraAlgorithm struct {
+	limiter *redis_rate.Limiter
+	limit   redis_rate.Limit
+}
+
+func (g *gcraAlgorithm) check(ctx context.Context, key string, n int) (bool, time.Duration, time.Duration, int, error) {
+	res, err := g.limiter.AllowN(ctx, key, g.limit, n)
+	if err != nil {
+		return false, 0, 0, 0, err
+	}
+	if res == nil {
+		return false, 0, 0, 0, ErrIntervalServer
+	}
+	return res.Allowed > 0, res.RetryAfter, res.ResetAfter, res.Remaining, nil
+}
+
+// windowBucket returns the index of the period-sized window now falls
+// into, and how long until that window ends.
+func windowBucket(period time.Duration) (bucket int64, resetAfter time.Duration) {
+	now := time.Now().UnixNano()
+	p := int64(period)
+	bucket = now / p
+	resetAfter = time.Duration(p - now%p)
+	return bucket, resetAfter
+}
+
+// fixedWindowAlgorithm counts requests in the current window with a
+// single INCR, expiring the window key once it's no longer needed.
+type fixedWindowAlgorithm struct {
+	rdb    redis.UniversalClient
+	rate   int
+	period time.Duration
+}
+
+func (f *fixedWindowAlgorithm) check(ctx context.Context, key string, n int) (bool, time.Duration, time.Duration, int, error) {
+	bucket, resetAfter := windowBucket(f.period)
+	windowKey := fmt.Sprintf("%s:fw:%d", key, bucket)
+
+	count, err := f.rdb.IncrBy(ctx, windowKey, int64(n)).Result()
+	if err != nil {
+		return false, 0, 0, 0, err
+	}
+	if count == int64(n) {
+		if err := f.rdb.Expire(ctx, windowKey, f.period+time.Second).Err(); err != nil {
+			return false, 0, 0, 0, err
+		}
+	}
+
+	remaining := f.rate - int(count)
+	if remaining < 0 {
+		remaining = 0
+	}
+
+	if count > int64(f.rate) {
+		return false, resetAfter, resetAfter, remaining, nil
+	}
+	return true, 0, resetAfter, remaining, nil
+}
+
+// slidingWindowAlgorithm approximates a sliding window by weighting the
+// previous fixed window's count by how much of it overlaps the sliding
+// window, per the "sliding window counter" pattern.
+type slidingWindowAlgorithm struct {
+	rdb    redis.UniversalClient
+	rate   int
+	period time.Duration
+}
+
+func (s *slidingWindowAlgorithm) check(ctx context.Context, key string, n int) (bool, time.Duration, time.Duration, int, error) {
+	bucket, resetAfter := windowBucket(s.period)
+	elapsed := s.period - resetAfter
+	overlap := 1 - float64(elapsed)/float64(s.period)
+
+	currKey := fmt.Sprintf("%s:sw:%d", key, bucket)
+	prevKey := fmt.Sprintf("%s:sw:%d", key, bucket-1)
+
+	prevCount, err := s.rdb.Get(ctx, prevKey).Int64()
+	if err != nil && err != redis.Nil {
+		return false, 0, 0, 0, err
+	}
+
+	currCount, err := s.rdb.IncrBy(ctx, currKey, int64(n)).Result()
+	if err != nil {
+		return false, 0, 0, 0, err
+	}
+	if currCount == int64(n) {
+		if err := s.rdb.Expire(ctx, currKey, 2*s.period).Err(); err != nil {
+			return false, 0, 0, 0, err
+		}
+	}
+
+	weighted := float64(prevCount)*overlap + float64(currCount)
+	remaining := s.rate - int(weighted)
+	if remaining < 0 {
+		remaining = 0
+	}
+
+	if weighted > float64(s.rate) {
+		return false, resetAfter, resetAfter, remaining, nil
+	}
+	return true, 0, resetAfter, remaining, nil
+}
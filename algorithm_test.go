@@ -0,0 +1,82 @@
+package ratelimiter
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLimiter_FixedWindowAllowsUpToRateThenBlocks(t *testing.T) {
+	rdb := newTestRedis(t)
+
+	limiter := New(
+		rdb,
+		"test:fixedwindow",
+		3,
+		Per(time.Minute),
+		WithAlgorithm(FixedWindow),
+	)
+
+	for i := 0; i < 3; i++ {
+		res, err := limiter.(NonBlockingLimiter).Allow(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error on request %d: %v", i, err)
+		}
+		if !res.OK {
+			t.Fatalf("expected request %d to be allowed", i)
+		}
+	}
+
+	res, err := limiter.(NonBlockingLimiter).Allow(context.Background())
+	if !errors.Is(err, ErrLimitExceeded) {
+		t.Fatalf("expected ErrLimitExceeded, got %v", err)
+	}
+	if res.OK {
+		t.Fatal("expected request past the fixed window rate to be denied")
+	}
+}
+
+func TestLimiter_SlidingWindowAllowsUpToRateThenBlocks(t *testing.T) {
+	rdb := newTestRedis(t)
+
+	limiter := New(
+		rdb,
+		"test:slidingwindow",
+		3,
+		Per(time.Minute),
+		WithAlgorithm(SlidingWindow),
+	)
+
+	for i := 0; i < 3; i++ {
+		res, err := limiter.(NonBlockingLimiter).Allow(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error on request %d: %v", i, err)
+		}
+		if !res.OK {
+			t.Fatalf("expected request %d to be allowed", i)
+		}
+	}
+
+	res, err := limiter.(NonBlockingLimiter).Allow(context.Background())
+	if !errors.Is(err, ErrLimitExceeded) {
+		t.Fatalf("expected ErrLimitExceeded, got %v", err)
+	}
+	if res.OK {
+		t.Fatal("expected request past the sliding window rate to be denied")
+	}
+}
+
+func TestLimiter_DefaultAlgorithmIsGCRA(t *testing.T) {
+	rdb := newTestRedis(t)
+
+	limiter := New(rdb, "test:defaultalgo", 1, Per(time.Minute))
+
+	rl, ok := limiter.(*redisLimiter)
+	if !ok {
+		t.Fatalf("expected *redisLimiter, got %T", limiter)
+	}
+	if _, ok := rl.algo.(*gcraAlgorithm); !ok {
+		t.Fatalf("expected default algorithm to be gcraAlgorithm, got %T", rl.algo)
+	}
+}
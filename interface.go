@@ -1,6 +1,9 @@
 package ratelimiter
 
-import "time"
+import (
+	"context"
+	"time"
+)
 
 type Limiter interface {
 	Take() (time.Time, error)
@@ -12,3 +15,41 @@ type CompositeLimiter interface {
 	// AddLimiter adds another limiter that must also pass
 	AddLimiter(limiter Limiter)
 }
+
+// Reservation describes the outcome of a non-blocking Allow/AllowN check.
+type Reservation struct {
+	// OK reports whether the request is allowed right now.
+	OK bool
+	// Remaining is the number of requests left in the current window.
+	Remaining int
+	// ResetAfter is how long until the limit fully resets.
+	ResetAfter time.Duration
+	// RetryAfter is how long the caller should wait before retrying.
+	// It is only meaningful when OK is false.
+	RetryAfter time.Duration
+}
+
+// NonBlockingLimiter is implemented by limiters that can report whether a
+// request would be allowed right now, instead of blocking until it is.
+// It lets HTTP middleware answer with 429 and a Retry-After header rather
+// than holding the request goroutine open inside Take().
+type NonBlockingLimiter interface {
+	// Allow checks out a single token without blocking. If the limit has
+	// already been exceeded it still returns the Reservation (so callers
+	// can read RetryAfter) alongside ErrLimitExceeded.
+	Allow(ctx context.Context) (Reservation, error)
+	// AllowN checks out n tokens at once without blocking, for callers
+	// with weighted request costs. Denial is reported the same way as
+	// Allow: a Reservation with OK false and a non-nil ErrLimitExceeded.
+	AllowN(ctx context.Context, n int) (Reservation, error)
+}
+
+// WaitingLimiter is implemented by limiters that can block up to a bound,
+// rather than indefinitely like Take. It mirrors x/time/rate.Limiter.Wait.
+type WaitingLimiter interface {
+	// Wait blocks until a token is available, ctx is done, or the wait
+	// would exceed ctx's deadline / the configured WithMaxWait bound,
+	// whichever is smaller — in which case it returns
+	// ErrWouldExceedDeadline immediately instead of blocking.
+	Wait(ctx context.Context) error
+}
@@ -0,0 +1,169 @@
+package ratelimiter
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// KeyedLimiter applies one rate limit configuration across many logical
+// keys (e.g. per-user or per-IP), sharing a single algorithm instance
+// instead of requiring callers to build and cache a Limiter per key
+// themselves. This maps onto middleware use cases like Envoy's ratelimit
+// service, which looks a limit up per descriptor rather than baking the
+// key in at construction time.
+type KeyedLimiter struct {
+	algo     algorithm
+	ctx      context.Context
+	maxKeys  int
+	keyTTL   time.Duration
+	observer Observer
+
+	failurePolicy   FailurePolicy
+	failClosedBlock time.Duration
+	backoffBase     time.Duration
+	backoffMax      time.Duration
+	backoffTries    int
+	maxWait         time.Duration
+
+	mu    sync.Mutex
+	cache map[string]*list.Element
+	lru   *list.List // front = most recently used
+}
+
+type keyedEntry struct {
+	key      string
+	limiter  *redisLimiter
+	expireAt time.Time
+}
+
+// NewKeyed creates a KeyedLimiter enforcing `rate` per Per() duration
+// (default: per second), independently for each key passed to TakeFor,
+// AllowFor, or For. Sub-limiters are cached in an LRU bounded by
+// WithMaxKeys (default 10000) and evicted early once idle past
+// WithKeyTTL (default 5 minutes), so long-lived processes with
+// high key cardinality don't grow the cache unbounded.
+func NewKeyed(rdb redis.UniversalClient, rate int, opts ...Option) *KeyedLimiter {
+	cfg := &config{
+		per:             time.Second,
+		ctx:             context.Background(),
+		maxKeys:         10000,
+		keyTTL:          5 * time.Minute,
+		failClosedBlock: defaultFailClosedBlock,
+		backoffBase:     defaultBackoffBase,
+		backoffMax:      defaultBackoffMax,
+		backoffTries:    defaultBackoffTries,
+	}
+
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	return &KeyedLimiter{
+		algo:            newAlgorithm(rdb, cfg.algorithm, rate, cfg.per),
+		ctx:             cfg.ctx,
+		maxKeys:         cfg.maxKeys,
+		keyTTL:          cfg.keyTTL,
+		observer:        cfg.observer,
+		failurePolicy:   cfg.failurePolicy,
+		failClosedBlock: cfg.failClosedBlock,
+		backoffBase:     cfg.backoffBase,
+		backoffMax:      cfg.backoffMax,
+		backoffTries:    cfg.backoffTries,
+		maxWait:         cfg.maxWait,
+		cache:           make(map[string]*list.Element),
+		lru:             list.New(),
+	}
+}
+
+// For returns the Limiter scoped to key, creating and caching it on first
+// use. The result also implements NonBlockingLimiter, and can be passed to
+// NewComposite like any other Limiter.
+func (k *KeyedLimiter) For(key string) Limiter {
+	return k.get(key)
+}
+
+// TakeFor blocks until a request tagged with key is allowed.
+func (k *KeyedLimiter) TakeFor(ctx context.Context, key string) (time.Time, error) {
+	return k.get(key).take(ctx)
+}
+
+// AllowFor checks out a single token for key without blocking.
+func (k *KeyedLimiter) AllowFor(ctx context.Context, key string) (Reservation, error) {
+	return k.get(key).Allow(ctx)
+}
+
+// WaitFor blocks until a request tagged with key is allowed, but gives up
+// early with ErrWouldExceedDeadline per the same rules as Wait.
+func (k *KeyedLimiter) WaitFor(ctx context.Context, key string) error {
+	return k.get(key).Wait(ctx)
+}
+
+// get returns the cached sub-limiter for key, creating one if needed, and
+// refreshes its position/expiry in the LRU.
+func (k *KeyedLimiter) get(key string) *redisLimiter {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	k.evictExpiredLocked()
+
+	if elem, ok := k.cache[key]; ok {
+		k.lru.MoveToFront(elem)
+		entry := elem.Value.(*keyedEntry)
+		entry.expireAt = time.Now().Add(k.keyTTL)
+		return entry.limiter
+	}
+
+	sub := &redisLimiter{
+		algo:            k.algo,
+		key:             key,
+		ctx:             k.ctx,
+		observer:        k.observer,
+		failurePolicy:   k.failurePolicy,
+		failClosedBlock: k.failClosedBlock,
+		backoffBase:     k.backoffBase,
+		backoffMax:      k.backoffMax,
+		backoffTries:    k.backoffTries,
+		maxWait:         k.maxWait,
+	}
+
+	elem := k.lru.PushFront(&keyedEntry{
+		key:      key,
+		limiter:  sub,
+		expireAt: time.Now().Add(k.keyTTL),
+	})
+	k.cache[key] = elem
+
+	if k.lru.Len() > k.maxKeys {
+		k.evictOldestLocked()
+	}
+
+	return sub
+}
+
+// evictExpiredLocked drops cache entries idle past keyTTL. Callers must
+// hold k.mu. Entries expire in LRU order since all share the same TTL.
+func (k *KeyedLimiter) evictExpiredLocked() {
+	now := time.Now()
+	for {
+		oldest := k.lru.Back()
+		if oldest == nil || oldest.Value.(*keyedEntry).expireAt.After(now) {
+			return
+		}
+		k.evictOldestLocked()
+	}
+}
+
+// evictOldestLocked removes the least recently used cache entry. Callers
+// must hold k.mu.
+func (k *KeyedLimiter) evictOldestLocked() {
+	oldest := k.lru.Back()
+	if oldest == nil {
+		return
+	}
+	k.lru.Remove(oldest)
+	delete(k.cache, oldest.Value.(*keyedEntry).key)
+}
@@ -0,0 +1,78 @@
+package ratelimiter
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestKeyedLimiter_TakeForIsolatesKeys(t *testing.T) {
+	rdb := newTestRedis(t)
+
+	keyed := NewKeyed(rdb, 2, Per(time.Second))
+
+	start := time.Now()
+	for i := 0; i < 2; i++ {
+		if _, err := keyed.TakeFor(context.Background(), "user:a"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := keyed.TakeFor(context.Background(), "user:b"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	elapsed := time.Since(start)
+	if elapsed > 200*time.Millisecond {
+		t.Fatalf("independent keys should not share quota, took %v", elapsed)
+	}
+}
+
+func TestKeyedLimiter_AllowForDoesNotBlock(t *testing.T) {
+	rdb := newTestRedis(t)
+
+	keyed := NewKeyed(rdb, 1, Per(time.Second))
+
+	res, err := keyed.AllowFor(context.Background(), "user:a")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !res.OK {
+		t.Fatalf("expected first AllowFor to succeed")
+	}
+
+	res, err = keyed.AllowFor(context.Background(), "user:a")
+	if !errors.Is(err, ErrLimitExceeded) {
+		t.Fatalf("expected ErrLimitExceeded, got %v", err)
+	}
+	if res.OK {
+		t.Fatalf("expected second AllowFor for the same key to be limited")
+	}
+}
+
+func TestKeyedLimiter_ForReusesCachedLimiter(t *testing.T) {
+	rdb := newTestRedis(t)
+
+	keyed := NewKeyed(rdb, 5, Per(time.Second))
+
+	a := keyed.For("user:a")
+	b := keyed.For("user:a")
+	if a != b {
+		t.Fatalf("expected For() to return the same cached sub-limiter for the same key")
+	}
+}
+
+func TestKeyedLimiter_EvictsLeastRecentlyUsedBeyondMaxKeys(t *testing.T) {
+	rdb := newTestRedis(t)
+
+	keyed := NewKeyed(rdb, 5, Per(time.Second), WithMaxKeys(1))
+
+	first := keyed.For("user:a")
+	keyed.For("user:b") // evicts "user:a"
+
+	again := keyed.For("user:a")
+	if first == again {
+		t.Fatalf("expected user:a's sub-limiter to be evicted and recreated")
+	}
+}
@@ -0,0 +1,51 @@
+package ratelimiter
+
+import (
+	"sync"
+	"time"
+)
+
+// minimumSpacingLimiter wraps another Limiter and additionally guarantees
+// at least `minimum` elapsed time between two successful Take() returns,
+// regardless of what the inner limiter allows. This composes "<=N/sec in
+// Redis" with ">=50ms between requests locally" without a second Redis
+// round trip.
+type minimumSpacingLimiter struct {
+	inner   Limiter
+	minimum time.Duration
+
+	mu          sync.Mutex
+	lastAllowed time.Time
+}
+
+// NewMinimum wraps inner with a floor on how often Take() can return
+// successfully, regardless of what inner itself would allow.
+func NewMinimum(inner Limiter, minimum time.Duration) Limiter {
+	return &minimumSpacingLimiter{
+		inner:   inner,
+		minimum: minimum,
+	}
+}
+
+// Take delegates to the inner limiter, then sleeps off any remaining gap
+// before returning so that callers never see successful Take()s closer
+// together than minimum.
+func (m *minimumSpacingLimiter) Take() (time.Time, error) {
+	now, err := m.inner.Take()
+	if err != nil {
+		return now, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.lastAllowed.IsZero() {
+		if wait := m.minimum - now.Sub(m.lastAllowed); wait > 0 {
+			time.Sleep(wait)
+			now = time.Now()
+		}
+	}
+
+	m.lastAllowed = now
+	return now, nil
+}
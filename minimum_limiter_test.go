@@ -0,0 +1,45 @@
+package ratelimiter
+
+import (
+	"testing"
+	"time"
+)
+
+// alwaysAllowLimiter is a Limiter that never blocks, used to isolate
+// minimumSpacingLimiter's own spacing behavior from an inner limiter.
+type alwaysAllowLimiter struct{}
+
+func (alwaysAllowLimiter) Take() (time.Time, error) {
+	return time.Now(), nil
+}
+
+func TestMinimumSpacingLimiter_EnforcesFloor(t *testing.T) {
+	limiter := NewMinimum(alwaysAllowLimiter{}, 50*time.Millisecond)
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if _, err := limiter.Take(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	elapsed := time.Since(start)
+
+	if elapsed < 100*time.Millisecond {
+		t.Fatalf("expected at least 2*50ms between 3 takes, took %v", elapsed)
+	}
+}
+
+func TestMinimumSpacingLimiter_PropagatesInnerError(t *testing.T) {
+	inner := erroringLimiter{}
+	limiter := NewMinimum(inner, 50*time.Millisecond)
+
+	if _, err := limiter.Take(); err != ErrIntervalServer {
+		t.Fatalf("expected inner error to propagate, got %v", err)
+	}
+}
+
+type erroringLimiter struct{}
+
+func (erroringLimiter) Take() (time.Time, error) {
+	return time.Time{}, ErrIntervalServer
+}
@@ -0,0 +1,19 @@
+package ratelimiter
+
+import "time"
+
+// Observer receives notifications about Take/Allow outcomes, for metrics
+// and dashboards. A single Observer may be shared across many limiters
+// (e.g. every child of a CompositeLimiter), and must be safe for
+// concurrent use.
+type Observer interface {
+	// OnAllowed is called when a request is allowed, after waiting
+	// `wait` (zero if it didn't have to wait at all).
+	OnAllowed(key string, wait time.Duration)
+	// OnLimited is called each time a limiter discovers it must wait
+	// retryAfter before it can re-check the limit for key.
+	OnLimited(key string, retryAfter time.Duration)
+	// OnError is called when the limiter's backing store returns an
+	// error instead of an allow/deny decision.
+	OnError(key string, err error)
+}
@@ -0,0 +1,61 @@
+package ratelimiter
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingObserver is an Observer that records every call it receives,
+// used to assert that limiters notify it correctly.
+type recordingObserver struct {
+	mu      sync.Mutex
+	allowed int
+	limited int
+	errored int
+}
+
+func (r *recordingObserver) OnAllowed(key string, wait time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.allowed++
+}
+
+func (r *recordingObserver) OnLimited(key string, retryAfter time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.limited++
+}
+
+func (r *recordingObserver) OnError(key string, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.errored++
+}
+
+func TestRedisLimiter_ObserverSeesAllowedAndLimited(t *testing.T) {
+	rdb := newTestRedis(t)
+
+	obs := &recordingObserver{}
+	limiter := New(rdb, "test:observer", 1, Per(time.Second), WithObserver(obs))
+
+	if _, err := limiter.Take(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	nb := limiter.(NonBlockingLimiter)
+	if _, err := nb.Allow(context.Background()); !errors.Is(err, ErrLimitExceeded) {
+		t.Fatalf("expected ErrLimitExceeded, got %v", err)
+	}
+
+	obs.mu.Lock()
+	defer obs.mu.Unlock()
+	if obs.allowed != 1 {
+		t.Fatalf("expected 1 OnAllowed call, got %d", obs.allowed)
+	}
+	if obs.limited != 1 {
+		t.Fatalf("expected 1 OnLimited call, got %d", obs.limited)
+	}
+}
@@ -0,0 +1,59 @@
+package ratelimiter
+
+import (
+	"errors"
+	"math/rand"
+	"time"
+)
+
+// FailurePolicy controls what a Limiter does when its backing store (e.g.
+// Redis) returns an error instead of an allow/deny decision.
+type FailurePolicy int
+
+const (
+	// FailOpen lets the request through on a backing-store error. This
+	// is the historical default: availability over strict enforcement.
+	FailOpen FailurePolicy = iota
+	// FailClosed blocks the caller for the duration set with
+	// WithFailClosedBlock and then rejects with ErrRedisUnavailable, so a store
+	// outage can't turn into unlimited throughput.
+	FailClosed
+	// FailWithBackoff retries the backing-store call with exponential
+	// backoff and jitter before giving up with ErrRedisUnavailable.
+	FailWithBackoff
+)
+
+var (
+	// ErrRedisUnavailable is returned when the backing store could not
+	// be reached after exhausting the configured FailurePolicy.
+	ErrRedisUnavailable = errors.New("ratelimiter: redis unavailable")
+	// ErrContextCanceled wraps ctx's cancellation cause when ctx is done
+	// before a request could be allowed, so callers can match on it with
+	// errors.Is regardless of the underlying cause.
+	ErrContextCanceled = errors.New("ratelimiter: context canceled")
+	// ErrLimitExceeded is returned alongside the Reservation by Allow and
+	// AllowN when the limit has already been exceeded, so callers can
+	// errors.Is it instead of only checking Reservation.OK.
+	ErrLimitExceeded = errors.New("ratelimiter: limit exceeded")
+	// ErrWouldExceedDeadline is returned by Wait when the required
+	// RetryAfter exceeds ctx's deadline or the configured WithMaxWait
+	// bound, so the caller can shed load instead of blocking.
+	ErrWouldExceedDeadline = errors.New("ratelimiter: wait would exceed deadline")
+)
+
+const (
+	defaultFailClosedBlock = time.Second
+	defaultBackoffBase     = 20 * time.Millisecond
+	defaultBackoffMax      = time.Second
+	defaultBackoffTries    = 5
+)
+
+// backoffDelay returns the delay before retry `attempt` (0-indexed) under
+// exponential backoff with full jitter, capped at max.
+func backoffDelay(base, max time.Duration, attempt int) time.Duration {
+	delay := base << attempt
+	if delay <= 0 || delay > max {
+		delay = max
+	}
+	return time.Duration(rand.Int63n(int64(delay) + 1))
+}
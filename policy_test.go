@@ -0,0 +1,121 @@
+package ratelimiter
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+func deadRedis() *redis.Client {
+	return redis.NewClient(&redis.Options{
+		Addr: "127.0.0.1:6390", // dead port, distinct from newTestRedis's 6379, nothing listening
+	})
+}
+
+func TestLimiter_FailClosedBlocksAndReturnsTypedError(t *testing.T) {
+	limiter := New(
+		deadRedis(),
+		"test:failclosed",
+		1,
+		Per(time.Second),
+		WithFailurePolicy(FailClosed),
+		WithFailClosedBlock(50*time.Millisecond),
+	)
+
+	start := time.Now()
+	_, err := limiter.Take()
+	elapsed := time.Since(start)
+
+	if !errors.Is(err, ErrRedisUnavailable) {
+		t.Fatalf("expected ErrRedisUnavailable, got %v", err)
+	}
+	if elapsed < 50*time.Millisecond {
+		t.Fatalf("expected FailClosed to block for at least 50ms, took %v", elapsed)
+	}
+}
+
+func TestLimiter_FailOpenDefaultSucceedsOnStoreError(t *testing.T) {
+	limiter := New(deadRedis(), "test:failopendefault", 1, Per(time.Second))
+
+	start := time.Now()
+	_, err := limiter.Take()
+	elapsed := time.Since(start)
+
+	if err != nil {
+		t.Fatalf("expected FailOpen to swallow the store error, got %v", err)
+	}
+	if elapsed > 200*time.Millisecond {
+		t.Fatalf("expected FailOpen to return quickly, took %v", elapsed)
+	}
+}
+
+func TestLimiter_FailWithBackoffRetriesThenReturnsTypedError(t *testing.T) {
+	limiter := New(
+		deadRedis(),
+		"test:failbackoff",
+		1,
+		Per(time.Second),
+		WithFailurePolicy(FailWithBackoff),
+		WithBackoff(5*time.Millisecond, 10*time.Millisecond, 3),
+	)
+
+	_, err := limiter.Take()
+	if !errors.Is(err, ErrRedisUnavailable) {
+		t.Fatalf("expected ErrRedisUnavailable after exhausting retries, got %v", err)
+	}
+}
+
+func TestLimiter_TakeReturnsContextCauseOnCancellation(t *testing.T) {
+	rdb := newTestRedis(t)
+
+	// Exhaust the limit so Take() has to wait on RetryAfter.
+	limiter := New(rdb, "test:cancel", 1, Per(time.Minute))
+	if _, err := limiter.Take(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithCancelCause(context.Background())
+	cause := errors.New("caller gave up")
+
+	blocked := New(rdb, "test:cancel", 1, Per(time.Minute), WithContext(ctx))
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := blocked.Take()
+		done <- err
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	cancel(cause)
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, cause) {
+			t.Fatalf("expected Take() to return the cancellation cause, got %v", err)
+		}
+		if !errors.Is(err, ErrContextCanceled) {
+			t.Fatalf("expected Take() to also match ErrContextCanceled, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Take() did not return after context cancellation")
+	}
+}
+
+func TestNewComposite_FailClosedPropagatesChildError(t *testing.T) {
+	composite := NewComposite([]Limiter{erroringLimiter{}}, WithFailurePolicy(FailClosed))
+
+	if _, err := composite.Take(); !errors.Is(err, ErrIntervalServer) {
+		t.Fatalf("expected child error to propagate under FailClosed, got %v", err)
+	}
+}
+
+func TestNewComposite_FailOpenSwallowsChildError(t *testing.T) {
+	composite := NewComposite([]Limiter{erroringLimiter{}})
+
+	if _, err := composite.Take(); err != nil {
+		t.Fatalf("expected FailOpen (default) to swallow the child error, got %v", err)
+	}
+}
@@ -0,0 +1,29 @@
+package prometheus
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestObserver_RecordsOutcomes(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	o := NewObserver(reg)
+
+	o.OnAllowed("test:key", 10*time.Millisecond)
+	o.OnLimited("test:key", 50*time.Millisecond)
+	o.OnError("test:key", errors.New("boom"))
+
+	if got := testutil.ToFloat64(o.allowedTotal.WithLabelValues("test:key")); got != 1 {
+		t.Fatalf("expected allowedTotal=1, got %v", got)
+	}
+	if got := testutil.ToFloat64(o.limitedTotal.WithLabelValues("test:key")); got != 1 {
+		t.Fatalf("expected limitedTotal=1, got %v", got)
+	}
+	if got := testutil.ToFloat64(o.errorsTotal.WithLabelValues("test:key")); got != 1 {
+		t.Fatalf("expected errorsTotal=1, got %v", got)
+	}
+}
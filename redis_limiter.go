@@ -3,10 +3,11 @@ package ratelimiter
 import (
 	"context"
 	"errors"
+	"fmt"
+	"math/rand"
 	"sync"
 	"time"
 
-	"github.com/go-redis/redis_rate/v10"
 	"github.com/redis/go-redis/v9"
 )
 
@@ -17,8 +18,18 @@ var (
 type Option func(*config)
 
 type config struct {
-	per time.Duration
-	ctx context.Context
+	per             time.Duration
+	ctx             context.Context
+	maxKeys         int
+	keyTTL          time.Duration
+	observer        Observer
+	failurePolicy   FailurePolicy
+	failClosedBlock time.Duration
+	backoffBase     time.Duration
+	backoffMax      time.Duration
+	backoffTries    int
+	algorithm       Algorithm
+	maxWait         time.Duration
 }
 
 func Per(d time.Duration) Option {
@@ -34,11 +45,81 @@ func WithContext(ctx context.Context) Option {
 	}
 }
 
+// WithMaxKeys bounds how many per-key sub-limiters a KeyedLimiter keeps
+// cached at once, evicting the least recently used key once the bound is
+// exceeded. It has no effect on New().
+func WithMaxKeys(n int) Option {
+	return func(c *config) {
+		c.maxKeys = n
+	}
+}
+
+// WithKeyTTL sets how long a KeyedLimiter keeps a per-key sub-limiter
+// cached after it was last used. It has no effect on New().
+func WithKeyTTL(d time.Duration) Option {
+	return func(c *config) {
+		c.keyTTL = d
+	}
+}
+
+// WithObserver wires an Observer into the limiter, notified of every
+// allow/limit/error outcome so callers can surface metrics without
+// reaching into Take()'s control flow themselves.
+func WithObserver(o Observer) Option {
+	return func(c *config) {
+		c.observer = o
+	}
+}
+
+// WithFailurePolicy selects what happens when the backing store errors
+// out instead of returning an allow/deny decision. Defaults to FailOpen.
+func WithFailurePolicy(p FailurePolicy) Option {
+	return func(c *config) {
+		c.failurePolicy = p
+	}
+}
+
+// WithFailClosedBlock sets how long FailClosed blocks the caller before
+// giving up with ErrRedisUnavailable. Defaults to one second.
+func WithFailClosedBlock(d time.Duration) Option {
+	return func(c *config) {
+		c.failClosedBlock = d
+	}
+}
+
+// WithBackoff configures the exponential backoff FailWithBackoff retries
+// the backing store with: base is the initial delay, max caps it, and
+// tries bounds how many attempts are made before giving up.
+func WithBackoff(base, max time.Duration, tries int) Option {
+	return func(c *config) {
+		c.backoffBase = base
+		c.backoffMax = max
+		c.backoffTries = tries
+	}
+}
+
+// WithMaxWait caps how long Wait will block before giving up with
+// ErrWouldExceedDeadline, independent of ctx's own deadline. It has no
+// effect on Take, Allow, or AllowN.
+func WithMaxWait(d time.Duration) Option {
+	return func(c *config) {
+		c.maxWait = d
+	}
+}
+
 type redisLimiter struct {
-	limiter *redis_rate.Limiter
-	key     string
-	limit   redis_rate.Limit
-	ctx     context.Context
+	algo algorithm
+	key  string
+	ctx  context.Context
+
+	observer Observer
+
+	failurePolicy   FailurePolicy
+	failClosedBlock time.Duration
+	backoffBase     time.Duration
+	backoffMax      time.Duration
+	backoffTries    int
+	maxWait         time.Duration
 }
 
 func New(
@@ -48,8 +129,12 @@ func New(
 	opts ...Option,
 ) Limiter {
 	cfg := &config{
-		per: time.Second,
-		ctx: context.Background(),
+		per:             time.Second,
+		ctx:             context.Background(),
+		failClosedBlock: defaultFailClosedBlock,
+		backoffBase:     defaultBackoffBase,
+		backoffMax:      defaultBackoffMax,
+		backoffTries:    defaultBackoffTries,
 	}
 
 	for _, opt := range opts {
@@ -57,37 +142,56 @@ func New(
 	}
 
 	return &redisLimiter{
-		limiter: redis_rate.NewLimiter(rdb),
-		key:     key,
-		limit:   redis_rate.Limit{Rate: rate, Burst: rate, Period: cfg.per},
-		ctx:     cfg.ctx,
+		algo:            newAlgorithm(rdb, cfg.algorithm, rate, cfg.per),
+		key:             key,
+		ctx:             cfg.ctx,
+		observer:        cfg.observer,
+		failurePolicy:   cfg.failurePolicy,
+		failClosedBlock: cfg.failClosedBlock,
+		backoffBase:     cfg.backoffBase,
+		backoffMax:      cfg.backoffMax,
+		backoffTries:    cfg.backoffTries,
+		maxWait:         cfg.maxWait,
 	}
 }
 
 func (l *redisLimiter) Take() (time.Time, error) {
+	return l.take(l.ctx)
+}
+
+// take is the shared blocking implementation behind Take(), parameterized
+// on ctx so KeyedLimiter can drive it with a per-call context instead of
+// the one bound at construction time.
+func (l *redisLimiter) take(ctx context.Context) (time.Time, error) {
+	start := time.Now()
 	for {
 		now := time.Now()
 
-		res, err := l.limiter.Allow(l.ctx, l.key, l.limit)
-		if err == nil && res.Allowed > 0 {
-			return now, nil
-		}
-
+		res, err := l.callAllow(ctx, 1)
 		if err != nil {
-			return time.Now(), err
+			if policyErr := l.onStoreError(ctx, err); policyErr != nil {
+				return time.Now(), policyErr
+			}
+			return time.Now(), nil // FailOpen
 		}
 
-		if res == nil {
-			return time.Now(), ErrIntervalServer
+		if res.allowed {
+			if l.observer != nil {
+				l.observer.OnAllowed(l.key, now.Sub(start))
+			}
+			return now, nil
 		}
 
 		// Rate limit exceeded, wait for RetryAfter
-		if res.RetryAfter > 0 {
+		if res.retryAfter > 0 {
+			if l.observer != nil {
+				l.observer.OnLimited(l.key, res.retryAfter)
+			}
 			select {
-			case <-time.After(res.RetryAfter):
+			case <-time.After(res.retryAfter):
 				continue
-			case <-l.ctx.Done():
-				return time.Now(), nil
+			case <-ctx.Done():
+				return time.Now(), ctxCanceledErr(ctx)
 			}
 		}
 
@@ -96,10 +200,200 @@ func (l *redisLimiter) Take() (time.Time, error) {
 	}
 }
 
+// Allow checks out a single token without blocking. See AllowN.
+func (l *redisLimiter) Allow(ctx context.Context) (Reservation, error) {
+	return l.AllowN(ctx, 1)
+}
+
+// AllowN checks out n tokens without blocking, surfacing the reservation
+// data redis_rate already computes instead of discarding it. When the
+// limit has already been exceeded it still returns the Reservation (so
+// callers can read RetryAfter) alongside ErrLimitExceeded, so callers can
+// match on Reservation.OK or errors.Is(err, ErrLimitExceeded) as they
+// prefer.
+func (l *redisLimiter) AllowN(ctx context.Context, n int) (Reservation, error) {
+	res, err := l.callAllow(ctx, n)
+	if err != nil {
+		if policyErr := l.onStoreError(ctx, err); policyErr != nil {
+			return Reservation{}, policyErr
+		}
+		return Reservation{OK: true}, nil // FailOpen
+	}
+
+	if l.observer != nil {
+		if res.allowed {
+			l.observer.OnAllowed(l.key, 0)
+		} else {
+			l.observer.OnLimited(l.key, res.retryAfter)
+		}
+	}
+
+	reservation := Reservation{
+		OK:         res.allowed,
+		Remaining:  res.remaining,
+		ResetAfter: res.resetAfter,
+		RetryAfter: res.retryAfter,
+	}
+	if !res.allowed {
+		return reservation, ErrLimitExceeded
+	}
+	return reservation, nil
+}
+
+// Wait blocks until a single token is available, but never for longer
+// than ctx's deadline or the configured WithMaxWait bound, whichever is
+// smaller. If the required wait would exceed that budget it returns
+// ErrWouldExceedDeadline immediately instead of sleeping and then
+// failing, so callers like HTTP handlers can shed load cheaply.
+func (l *redisLimiter) Wait(ctx context.Context) error {
+	start := time.Now()
+	for {
+		res, err := l.callAllow(ctx, 1)
+		if err != nil {
+			if policyErr := l.onStoreError(ctx, err); policyErr != nil {
+				return policyErr
+			}
+			return nil // FailOpen
+		}
+
+		if res.allowed {
+			if l.observer != nil {
+				l.observer.OnAllowed(l.key, time.Since(start))
+			}
+			return nil
+		}
+
+		if l.observer != nil {
+			l.observer.OnLimited(l.key, res.retryAfter)
+		}
+
+		wait := res.retryAfter + waitJitter(res.retryAfter)
+
+		budget := l.maxWait - time.Since(start)
+		hasBudget := l.maxWait > 0
+		if deadline, ok := ctx.Deadline(); ok {
+			if remaining := time.Until(deadline); !hasBudget || remaining < budget {
+				budget = remaining
+				hasBudget = true
+			}
+		}
+		if hasBudget && wait > budget {
+			return ErrWouldExceedDeadline
+		}
+
+		select {
+		case <-time.After(wait):
+			continue
+		case <-ctx.Done():
+			return ctxCanceledErr(ctx)
+		}
+	}
+}
+
+// ctxCanceledErr wraps ctx's cancellation cause with ErrContextCanceled so
+// callers can match on either with errors.Is.
+func ctxCanceledErr(ctx context.Context) error {
+	return fmt.Errorf("%w: %w", ErrContextCanceled, context.Cause(ctx))
+}
+
+// waitJitter adds up to 10% random jitter on top of a wait duration, so
+// callers blocked on the same key don't all retry in lockstep.
+func waitJitter(d time.Duration) time.Duration {
+	if d <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(d)/10 + 1))
+}
+
+// checkResult is algorithm.check's outcome, normalized to a struct so
+// callAllow's retry loop has a single value to pass around.
+type checkResult struct {
+	allowed    bool
+	remaining  int
+	resetAfter time.Duration
+	retryAfter time.Duration
+}
+
+// callAllow checks out n tokens against l.algo, retrying with exponential
+// backoff and jitter when the limiter's FailurePolicy is FailWithBackoff.
+func (l *redisLimiter) callAllow(ctx context.Context, n int) (*checkResult, error) {
+	if l.failurePolicy != FailWithBackoff {
+		return l.doCheck(ctx, n)
+	}
+
+	tries := l.backoffTries
+	if tries <= 0 {
+		tries = defaultBackoffTries
+	}
+
+	var lastErr error
+	for attempt := 0; attempt < tries; attempt++ {
+		res, err := l.doCheck(ctx, n)
+		if err == nil {
+			return res, nil
+		}
+		lastErr = err
+
+		select {
+		case <-time.After(backoffDelay(l.backoffBase, l.backoffMax, attempt)):
+		case <-ctx.Done():
+			return nil, ctxCanceledErr(ctx)
+		}
+	}
+
+	return nil, lastErr
+}
+
+// doCheck makes a single call to l.algo.check.
+func (l *redisLimiter) doCheck(ctx context.Context, n int) (*checkResult, error) {
+	allowed, retryAfter, resetAfter, remaining, err := l.algo.check(ctx, l.key, n)
+	if err != nil {
+		return nil, err
+	}
+	return &checkResult{
+		allowed:    allowed,
+		remaining:  remaining,
+		resetAfter: resetAfter,
+		retryAfter: retryAfter,
+	}, nil
+}
+
+// onStoreError applies the limiter's FailurePolicy to a backing-store
+// error. It returns nil if the caller should be let through (FailOpen),
+// or a non-nil error the caller should be rejected with.
+func (l *redisLimiter) onStoreError(ctx context.Context, err error) error {
+	if l.observer != nil {
+		l.observer.OnError(l.key, err)
+	}
+
+	if ctx.Err() != nil {
+		return ctxCanceledErr(ctx)
+	}
+
+	switch l.failurePolicy {
+	case FailClosed:
+		block := l.failClosedBlock
+		if block <= 0 {
+			block = defaultFailClosedBlock
+		}
+		select {
+		case <-time.After(block):
+		case <-ctx.Done():
+			return ctxCanceledErr(ctx)
+		}
+		return fmt.Errorf("%w: %v", ErrRedisUnavailable, err)
+	case FailWithBackoff:
+		return fmt.Errorf("%w: %v", ErrRedisUnavailable, err)
+	default: // FailOpen
+		return nil
+	}
+}
+
 // compositeLimiter applies multiple rate limits and only passes if all limits pass
 type compositeLimiter struct {
-	limiters []Limiter
-	mu       sync.RWMutex
+	limiters      []Limiter
+	mu            sync.RWMutex
+	failurePolicy FailurePolicy
 }
 
 // NewComposite creates a new composite limiter that checks multiple rate limits.
@@ -109,11 +403,28 @@ type compositeLimiter struct {
 //
 //	perSecLimiter := New(rdb, "aggregate_per_second", 10, Per(time.Second))
 //	perMinLimiter := New(rdb, "aggregate_per_per_min", 100, Per(time.Minute))
-//	composite := NewComposite(perSecLimiter, perMinLimiter)
+//	composite := NewComposite([]Limiter{perSecLimiter, perMinLimiter})
 //	composite.Take() // will check both limits, only passes if both allow
-func NewComposite(limiters ...Limiter) CompositeLimiter {
+//
+// WithFailurePolicy selects what Take() does when a child limiter
+// returns an error: FailOpen (the default) lets the request through,
+// while FailClosed/FailWithBackoff propagate the child's error instead.
+//
+// compositeLimiter has no Observer of its own and does not report which
+// child blocked a request; on success it returns the last child's time.
+// To attribute a block to a specific child limiter, give each child its
+// own key and wire the same Observer into all of them via
+// New(..., WithObserver(obs)) — obs.OnLimited's key identifies which
+// child is rate-limiting the caller.
+func NewComposite(limiters []Limiter, opts ...Option) CompositeLimiter {
+	cfg := &config{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
 	return &compositeLimiter{
-		limiters: limiters,
+		limiters:      limiters,
+		failurePolicy: cfg.failurePolicy,
 	}
 }
 
@@ -146,8 +457,12 @@ func (c *compositeLimiter) Take() (time.Time, error) {
 	for _, limiter := range limiters {
 		lastTime, err = limiter.Take()
 		if err != nil {
-			// On error, fail open
-			return time.Now(), err
+			switch c.failurePolicy {
+			case FailClosed, FailWithBackoff:
+				return time.Time{}, err
+			default: // FailOpen
+				return time.Now(), nil
+			}
 		}
 	}
 
@@ -2,6 +2,7 @@ package ratelimiter
 
 import (
 	"context"
+	"errors"
 	"sync"
 	"testing"
 	"time"
@@ -156,6 +157,69 @@ func TestLimiter_FailOpen(t *testing.T) {
 	_ = err // error handling is implementation-dependent for fail open
 }
 
+func TestLimiter_AllowDoesNotBlock(t *testing.T) {
+	rdb := newTestRedis(t)
+
+	limiter := New(rdb, "test:allow", 1, Per(time.Second))
+	nb, ok := limiter.(NonBlockingLimiter)
+	if !ok {
+		t.Fatalf("New() does not implement NonBlockingLimiter")
+	}
+
+	start := time.Now()
+	res, err := nb.Allow(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !res.OK {
+		t.Fatalf("expected first Allow to succeed")
+	}
+
+	res, err = nb.Allow(context.Background())
+	elapsed := time.Since(start)
+	if !errors.Is(err, ErrLimitExceeded) {
+		t.Fatalf("expected ErrLimitExceeded, got %v", err)
+	}
+	if res.OK {
+		t.Fatalf("expected second Allow to be limited")
+	}
+	if res.RetryAfter <= 0 {
+		t.Fatalf("expected RetryAfter to be set, got %v", res.RetryAfter)
+	}
+	if elapsed > 100*time.Millisecond {
+		t.Fatalf("Allow should not block, took %v", elapsed)
+	}
+}
+
+func TestLimiter_AllowN(t *testing.T) {
+	rdb := newTestRedis(t)
+
+	limiter := New(rdb, "test:allown", 5, Per(time.Second))
+	nb, ok := limiter.(NonBlockingLimiter)
+	if !ok {
+		t.Fatalf("New() does not implement NonBlockingLimiter")
+	}
+
+	res, err := nb.AllowN(context.Background(), 5)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !res.OK {
+		t.Fatalf("expected AllowN(5) within a 5/sec limit to succeed")
+	}
+	if res.Remaining != 0 {
+		t.Fatalf("expected no tokens remaining, got %d", res.Remaining)
+	}
+
+	res, err = nb.AllowN(context.Background(), 1)
+	if !errors.Is(err, ErrLimitExceeded) {
+		t.Fatalf("expected ErrLimitExceeded, got %v", err)
+	}
+	if res.OK {
+		t.Fatalf("expected AllowN(1) to be limited once the burst is spent")
+	}
+}
+
 func TestCompositeLimiter_BothLimitsMustPass(t *testing.T) {
 	rdb := newTestRedis(t)
 
@@ -163,7 +227,7 @@ func TestCompositeLimiter_BothLimitsMustPass(t *testing.T) {
 	perSec5 := New(rdb, "test:composite:1", 5, Per(time.Second))
 	perSec3 := New(rdb, "test:composite:2", 3, Per(time.Second))
 
-	composite := NewComposite(perSec5, perSec3)
+	composite := NewComposite([]Limiter{perSec5, perSec3})
 
 	// Should pass for the first 3 requests (both allow)
 	start := time.Now()
@@ -219,7 +283,7 @@ func TestCompositeLimiter_PerSecondAndPerMinute(t *testing.T) {
 	perSec := New(rdb, "test:composite:persec", 10, Per(time.Second))
 	perMin := New(rdb, "test:composite:permin", 30, Per(time.Minute))
 
-	composite := NewComposite(perSec, perMin)
+	composite := NewComposite([]Limiter{perSec, perMin})
 
 	// First 10 requests should pass quickly (within per-second limit)
 	start := time.Now()
@@ -253,7 +317,7 @@ func TestCompositeLimiter_AddLimiter(t *testing.T) {
 	rdb := newTestRedis(t)
 
 	limiter1 := New(rdb, "test:composite:add:1", 5, Per(time.Second))
-	composite := NewComposite(limiter1)
+	composite := NewComposite([]Limiter{limiter1})
 
 	// Add a second limiter dynamically
 	limiter2 := New(rdb, "test:composite:add:2", 3, Per(time.Second))
@@ -337,7 +401,7 @@ func BenchmarkLimiter_Take_WithBlocking(b *testing.B) {
 func BenchmarkCompositeLimiter_Take_SingleLimiter(b *testing.B) {
 	rdb := newBenchmarkRedis(b)
 	limiter1 := New(rdb, "bench:composite:single:1", 10000, Per(time.Second))
-	composite := NewComposite(limiter1)
+	composite := NewComposite([]Limiter{limiter1})
 
 	b.ResetTimer()
 	b.ReportAllocs()
@@ -353,7 +417,7 @@ func BenchmarkCompositeLimiter_Take_TwoLimiters(b *testing.B) {
 	rdb := newBenchmarkRedis(b)
 	limiter1 := New(rdb, "bench:composite:two:1", 10000, Per(time.Second))
 	limiter2 := New(rdb, "bench:composite:two:2", 10000, Per(time.Second))
-	composite := NewComposite(limiter1, limiter2)
+	composite := NewComposite([]Limiter{limiter1, limiter2})
 
 	b.ResetTimer()
 	b.ReportAllocs()
@@ -370,7 +434,7 @@ func BenchmarkCompositeLimiter_Take_ThreeLimiters(b *testing.B) {
 	limiter1 := New(rdb, "bench:composite:three:1", 10000, Per(time.Second))
 	limiter2 := New(rdb, "bench:composite:three:2", 10000, Per(time.Second))
 	limiter3 := New(rdb, "bench:composite:three:3", 10000, Per(time.Second))
-	composite := NewComposite(limiter1, limiter2, limiter3)
+	composite := NewComposite([]Limiter{limiter1, limiter2, limiter3})
 
 	b.ResetTimer()
 	b.ReportAllocs()
@@ -386,7 +450,7 @@ func BenchmarkCompositeLimiter_Take_Concurrent(b *testing.B) {
 	rdb := newBenchmarkRedis(b)
 	limiter1 := New(rdb, "bench:composite:concurrent:1", 10000, Per(time.Second))
 	limiter2 := New(rdb, "bench:composite:concurrent:2", 10000, Per(time.Second))
-	composite := NewComposite(limiter1, limiter2)
+	composite := NewComposite([]Limiter{limiter1, limiter2})
 
 	b.ResetTimer()
 	b.ReportAllocs()
@@ -403,7 +467,7 @@ func BenchmarkCompositeLimiter_Take_Concurrent(b *testing.B) {
 func BenchmarkCompositeLimiter_AddLimiter(b *testing.B) {
 	rdb := newBenchmarkRedis(b)
 	limiter1 := New(rdb, "bench:composite:add:1", 10000, Per(time.Second))
-	composite := NewComposite(limiter1)
+	composite := NewComposite([]Limiter{limiter1})
 
 	b.ResetTimer()
 	b.ReportAllocs()
@@ -0,0 +1,41 @@
+package ratelimiter
+
+import "time"
+
+// TickerLimiter spaces Take() calls at least interval/limit apart using a
+// single time.Ticker, with no Redis round trip. It's useful as a cheap
+// first stage inside a CompositeLimiter to enforce a strict minimum gap
+// between operations, or as a fallback Limiter when Redis is unavailable.
+type TickerLimiter struct {
+	ticker *time.Ticker
+}
+
+// NewTicker creates a Limiter that allows at most limit operations per
+// interval, spaced evenly via a ticker rather than allowed in a burst.
+// limit is clamped to at least 1, and the resulting tick is clamped to at
+// least 1ns, so a bad limit/interval pair can't divide down to zero and
+// panic inside time.NewTicker. Call Close on the returned *TickerLimiter
+// once it's no longer needed.
+func NewTicker(limit int, interval time.Duration) *TickerLimiter {
+	if limit <= 0 {
+		limit = 1
+	}
+	tick := interval / time.Duration(limit)
+	if tick <= 0 {
+		tick = time.Nanosecond
+	}
+	return &TickerLimiter{
+		ticker: time.NewTicker(tick),
+	}
+}
+
+// Take blocks until the next tick.
+func (t *TickerLimiter) Take() (time.Time, error) {
+	<-t.ticker.C
+	return time.Now(), nil
+}
+
+// Close stops the underlying ticker, releasing its resources.
+func (t *TickerLimiter) Close() {
+	t.ticker.Stop()
+}
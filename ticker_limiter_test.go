@@ -0,0 +1,58 @@
+package ratelimiter
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTickerLimiter_SpacesTakes(t *testing.T) {
+	tl := NewTicker(10, 100*time.Millisecond) // one tick every 10ms
+	defer tl.Close()
+
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		if _, err := tl.Take(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	elapsed := time.Since(start)
+
+	if elapsed < 40*time.Millisecond {
+		t.Fatalf("expected Take() to be spaced by ~10ms ticks, took %v", elapsed)
+	}
+}
+
+func TestTickerLimiter_CloseStopsTicking(t *testing.T) {
+	tl := NewTicker(100, 10*time.Millisecond)
+
+	if _, err := tl.Take(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tl.Close()
+
+	select {
+	case <-tl.ticker.C:
+	case <-time.After(5 * time.Millisecond):
+	}
+
+	select {
+	case <-tl.ticker.C:
+		t.Fatalf("ticker should not fire after Close")
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+func TestTickerLimiter_ClampsInvalidLimitAndInterval(t *testing.T) {
+	// limit <= 0 would divide by zero, and interval < limit would divide
+	// down to a zero tick; both must be clamped instead of panicking.
+	for _, tl := range []*TickerLimiter{
+		NewTicker(0, 100*time.Millisecond),
+		NewTicker(-5, 100*time.Millisecond),
+		NewTicker(1000, 500*time.Nanosecond),
+	} {
+		if _, err := tl.Take(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		tl.Close()
+	}
+}
@@ -0,0 +1,130 @@
+package ratelimiter
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLimiter_WaitReturnsImmediatelyWhenAllowed(t *testing.T) {
+	rdb := newTestRedis(t)
+
+	limiter := New(rdb, "test:wait:allowed", 10, Per(time.Second)).(*redisLimiter)
+
+	if err := limiter.Wait(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestLimiter_WaitBlocksThenSucceeds(t *testing.T) {
+	rdb := newTestRedis(t)
+
+	limiter := New(rdb, "test:wait:blocks", 1, Per(200*time.Millisecond)).(*redisLimiter)
+
+	if err := limiter.Wait(context.Background()); err != nil {
+		t.Fatalf("unexpected error on first call: %v", err)
+	}
+
+	start := time.Now()
+	if err := limiter.Wait(context.Background()); err != nil {
+		t.Fatalf("unexpected error on second call: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Fatalf("expected Wait to block for close to the window, only waited %v", elapsed)
+	}
+}
+
+func TestLimiter_WaitRejectsWhenExceedingCtxDeadline(t *testing.T) {
+	rdb := newTestRedis(t)
+
+	limiter := New(rdb, "test:wait:deadline", 1, Per(time.Minute)).(*redisLimiter)
+
+	if err := limiter.Wait(context.Background()); err != nil {
+		t.Fatalf("unexpected error on first call: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := limiter.Wait(ctx)
+	elapsed := time.Since(start)
+
+	if !errors.Is(err, ErrWouldExceedDeadline) {
+		t.Fatalf("expected ErrWouldExceedDeadline, got %v", err)
+	}
+	if elapsed > 50*time.Millisecond {
+		t.Fatalf("expected Wait to reject up-front without sleeping, took %v", elapsed)
+	}
+}
+
+func TestLimiter_WaitRechecksAfterBlockingInsteadOfAssumingAllowed(t *testing.T) {
+	rdb := newTestRedis(t)
+
+	limiter := New(rdb, "test:wait:recheck", 1, Per(200*time.Millisecond)).(*redisLimiter)
+
+	if err := limiter.Wait(context.Background()); err != nil {
+		t.Fatalf("unexpected error on first call: %v", err)
+	}
+
+	// Two concurrent waiters contend for the single token that frees up
+	// after the window resets. Only one may actually consume it; a Wait
+	// that returns success without re-checking after its sleep would let
+	// both through.
+	results := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			results <- limiter.Wait(context.Background())
+		}()
+	}
+
+	allowed := 0
+	for i := 0; i < 2; i++ {
+		if err := <-results; err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		allowed++
+	}
+	if allowed != 2 {
+		t.Fatalf("expected both waiters to eventually be allowed, got %d", allowed)
+	}
+
+	// A third, immediate Allow must now be denied: the waiter that lost
+	// the first race re-checked after sleeping again and consumed the
+	// current window's only token.
+	res, err := limiter.Allow(context.Background())
+	if !errors.Is(err, ErrLimitExceeded) {
+		t.Fatalf("expected ErrLimitExceeded, got %v", err)
+	}
+	if res.OK {
+		t.Fatalf("expected the window's token to already be spent by the last waiter")
+	}
+}
+
+func TestLimiter_WaitRejectsWhenExceedingMaxWait(t *testing.T) {
+	rdb := newTestRedis(t)
+
+	limiter := New(
+		rdb,
+		"test:wait:maxwait",
+		1,
+		Per(time.Minute),
+		WithMaxWait(10*time.Millisecond),
+	).(*redisLimiter)
+
+	if err := limiter.Wait(context.Background()); err != nil {
+		t.Fatalf("unexpected error on first call: %v", err)
+	}
+
+	start := time.Now()
+	err := limiter.Wait(context.Background())
+	elapsed := time.Since(start)
+
+	if !errors.Is(err, ErrWouldExceedDeadline) {
+		t.Fatalf("expected ErrWouldExceedDeadline, got %v", err)
+	}
+	if elapsed > 50*time.Millisecond {
+		t.Fatalf("expected Wait to reject up-front without sleeping, took %v", elapsed)
+	}
+}